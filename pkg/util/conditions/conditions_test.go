@@ -0,0 +1,182 @@
+/*
+ Copyright 2023 The Kapacity Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetStatusCondition(t *testing.T) {
+	past := metav1.NewTime(metav1.Now().Add(-time.Hour))
+
+	t.Run("appends new condition with LastTransitionTime set", func(t *testing.T) {
+		var conditions []metav1.Condition
+		changed := SetStatusCondition(&conditions, metav1.Condition{
+			Type:   "Ready",
+			Status: metav1.ConditionTrue,
+			Reason: "Ready",
+		})
+		if !changed {
+			t.Fatalf("changed = false, want true")
+		}
+		if len(conditions) != 1 {
+			t.Fatalf("len(conditions) = %d, want 1", len(conditions))
+		}
+		if conditions[0].LastTransitionTime.IsZero() {
+			t.Errorf("LastTransitionTime not set on new condition")
+		}
+	})
+
+	t.Run("same status preserves LastTransitionTime", func(t *testing.T) {
+		conditions := []metav1.Condition{{
+			Type:               "Ready",
+			Status:             metav1.ConditionTrue,
+			Reason:             "OldReason",
+			LastTransitionTime: past,
+		}}
+		changed := SetStatusCondition(&conditions, metav1.Condition{
+			Type:   "Ready",
+			Status: metav1.ConditionTrue,
+			Reason: "NewReason",
+		})
+		if !changed {
+			t.Fatalf("changed = false, want true (reason changed)")
+		}
+		if !conditions[0].LastTransitionTime.Equal(&past) {
+			t.Errorf("LastTransitionTime = %v, want unchanged %v", conditions[0].LastTransitionTime, past)
+		}
+		if conditions[0].Reason != "NewReason" {
+			t.Errorf("Reason = %v, want NewReason", conditions[0].Reason)
+		}
+	})
+
+	t.Run("status change bumps LastTransitionTime", func(t *testing.T) {
+		conditions := []metav1.Condition{{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: past,
+		}}
+		changed := SetStatusCondition(&conditions, metav1.Condition{
+			Type:   "Ready",
+			Status: metav1.ConditionTrue,
+		})
+		if !changed {
+			t.Fatalf("changed = false, want true")
+		}
+		if conditions[0].LastTransitionTime.Equal(&past) {
+			t.Errorf("LastTransitionTime not updated on status change")
+		}
+	})
+
+	t.Run("no-op when nothing changed", func(t *testing.T) {
+		conditions := []metav1.Condition{{
+			Type:               "Ready",
+			Status:             metav1.ConditionTrue,
+			Reason:             "Ready",
+			Message:            "all good",
+			LastTransitionTime: past,
+		}}
+		changed := SetStatusCondition(&conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionTrue,
+			Reason:  "Ready",
+			Message: "all good",
+		})
+		if changed {
+			t.Errorf("changed = true, want false")
+		}
+		if !conditions[0].LastTransitionTime.Equal(&past) {
+			t.Errorf("LastTransitionTime = %v, want unchanged %v", conditions[0].LastTransitionTime, past)
+		}
+	})
+}
+
+func TestFindStatusCondition(t *testing.T) {
+	conditions := []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue}}
+
+	if got := FindStatusCondition(conditions, "Ready"); got == nil {
+		t.Errorf("FindStatusCondition() = nil, want found")
+	}
+	if got := FindStatusCondition(conditions, "Other"); got != nil {
+		t.Errorf("FindStatusCondition() = %v, want nil", got)
+	}
+}
+
+func TestRemoveStatusCondition(t *testing.T) {
+	t.Run("present", func(t *testing.T) {
+		conditions := []metav1.Condition{
+			{Type: "Ready", Status: metav1.ConditionTrue},
+			{Type: "Other", Status: metav1.ConditionFalse},
+		}
+		if removed := RemoveStatusCondition(&conditions, "Ready"); !removed {
+			t.Fatalf("removed = false, want true")
+		}
+		if len(conditions) != 1 || conditions[0].Type != "Other" {
+			t.Errorf("conditions = %v, want only Other", conditions)
+		}
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		conditions := []metav1.Condition{{Type: "Other", Status: metav1.ConditionFalse}}
+		if removed := RemoveStatusCondition(&conditions, "Ready"); removed {
+			t.Errorf("removed = true, want false")
+		}
+		if len(conditions) != 1 {
+			t.Errorf("conditions = %v, want unchanged", conditions)
+		}
+	})
+}
+
+func TestIsStatusConditionHelpers(t *testing.T) {
+	conditions := []metav1.Condition{
+		{Type: "Ready", Status: metav1.ConditionTrue},
+		{Type: "Degraded", Status: metav1.ConditionFalse},
+	}
+
+	if !IsStatusConditionTrue(conditions, "Ready") {
+		t.Errorf("IsStatusConditionTrue(Ready) = false, want true")
+	}
+	if IsStatusConditionTrue(conditions, "Degraded") {
+		t.Errorf("IsStatusConditionTrue(Degraded) = true, want false")
+	}
+	if IsStatusConditionTrue(conditions, "Absent") {
+		t.Errorf("IsStatusConditionTrue(Absent) = true, want false")
+	}
+
+	if IsStatusConditionFalse(conditions, "Ready") {
+		t.Errorf("IsStatusConditionFalse(Ready) = true, want false")
+	}
+	if !IsStatusConditionFalse(conditions, "Degraded") {
+		t.Errorf("IsStatusConditionFalse(Degraded) = false, want true")
+	}
+	if IsStatusConditionFalse(conditions, "Absent") {
+		t.Errorf("IsStatusConditionFalse(Absent) = true, want false")
+	}
+
+	if !IsStatusConditionPresentAndEqual(conditions, "Ready", metav1.ConditionTrue) {
+		t.Errorf("IsStatusConditionPresentAndEqual(Ready, True) = false, want true")
+	}
+	if IsStatusConditionPresentAndEqual(conditions, "Ready", metav1.ConditionFalse) {
+		t.Errorf("IsStatusConditionPresentAndEqual(Ready, False) = true, want false")
+	}
+	if IsStatusConditionPresentAndEqual(conditions, "Absent", metav1.ConditionTrue) {
+		t.Errorf("IsStatusConditionPresentAndEqual(Absent, True) = true, want false")
+	}
+}
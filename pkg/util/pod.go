@@ -18,6 +18,11 @@
 package util
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -31,6 +36,61 @@ func GetPodNames(pods []*corev1.Pod) []string {
 	return result
 }
 
+// FilterActivePods returns the subset of pods for which IsPodActive returns true.
+func FilterActivePods(pods []*corev1.Pod) []*corev1.Pod {
+	result := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if IsPodActive(pod) {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+// FilterReadyPods returns the subset of pods for which IsPodReady returns true.
+func FilterReadyPods(pods []*corev1.Pod) []*corev1.Pod {
+	result := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if IsPodReady(pod) {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+// FilterAvailablePods returns the subset of pods for which IsPodAvailable returns true given minReadySeconds and now.
+func FilterAvailablePods(pods []*corev1.Pod, minReadySeconds int32, now metav1.Time) []*corev1.Pod {
+	result := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if IsPodAvailable(pod, minReadySeconds, now) {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+// CountReadyPods returns the number of pods for which IsPodReady returns true.
+func CountReadyPods(pods []*corev1.Pod) int {
+	count := 0
+	for _, pod := range pods {
+		if IsPodReady(pod) {
+			count++
+		}
+	}
+	return count
+}
+
+// CountAvailablePods returns the number of pods for which IsPodAvailable returns true given minReadySeconds and now.
+func CountAvailablePods(pods []*corev1.Pod, minReadySeconds int32, now metav1.Time) int {
+	count := 0
+	for _, pod := range pods {
+		if IsPodAvailable(pod, minReadySeconds, now) {
+			count++
+		}
+	}
+	return count
+}
+
 // IsPodRunning returns if the given pod's phase is running and is not being deleted.
 func IsPodRunning(pod *corev1.Pod) bool {
 	return pod.DeletionTimestamp.IsZero() && pod.Status.Phase == corev1.PodRunning
@@ -61,6 +121,40 @@ func GetPodReadyCondition(status corev1.PodStatus) *corev1.PodCondition {
 	return condition
 }
 
+// IsPodAvailable returns true if a pod is available; false otherwise.
+// Precondition for an available pod is that it is ready. On top of that, the readiness is confirmed for at
+// least minReadySeconds.
+func IsPodAvailable(pod *corev1.Pod, minReadySeconds int32, now metav1.Time) bool {
+	if !IsPodReady(pod) {
+		return false
+	}
+
+	c := GetPodReadyCondition(pod.Status)
+	minReadySecondsDuration := time.Duration(minReadySeconds) * time.Second
+	if minReadySeconds == 0 || (!c.LastTransitionTime.IsZero() && !c.LastTransitionTime.Add(minReadySecondsDuration).After(now.Time)) {
+		return true
+	}
+	return false
+}
+
+// IsContainersReady returns true if a pod's containers are all ready; false otherwise.
+func IsContainersReady(pod *corev1.Pod) bool {
+	return IsContainersReadyConditionTrue(pod.Status)
+}
+
+// IsContainersReadyConditionTrue returns true if a pod's containers are all ready; false otherwise.
+func IsContainersReadyConditionTrue(status corev1.PodStatus) bool {
+	condition := GetContainersReadyCondition(status)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// GetContainersReadyCondition extracts the containers ready condition from the given status and returns that.
+// Returns nil if the condition is not present.
+func GetContainersReadyCondition(status corev1.PodStatus) *corev1.PodCondition {
+	_, condition := GetPodCondition(&status, corev1.ContainersReady)
+	return condition
+}
+
 // GetPodCondition extracts the provided condition from the given status and returns that.
 // Returns nil and -1 if the condition is not present, and the index of the located condition.
 func GetPodCondition(status *corev1.PodStatus, conditionType corev1.PodConditionType) (int, *corev1.PodCondition) {
@@ -135,3 +229,96 @@ func AddPodReadinessGate(spec *corev1.PodSpec, conditionType corev1.PodCondition
 	spec.ReadinessGates = append(spec.ReadinessGates, corev1.PodReadinessGate{ConditionType: conditionType})
 	return true
 }
+
+// Reason values used by GeneratePodReadyCondition.
+const (
+	// ContainersNotReady is the reason used when one or more of the pod's containers are not ready.
+	ContainersNotReady = "ContainersNotReady"
+	// ReadinessGatesNotReady is the reason used when one or more of the pod's readiness gates are not ready.
+	ReadinessGatesNotReady = "ReadinessGatesNotReady"
+)
+
+// GeneratePodReadyCondition returns the aggregate PodReady condition computed from the pod's containers and
+// readiness gates, following the same algorithm as the kubelet: the pod is only ready once every container
+// declared in spec.Containers has a matching, ready entry in containerStatuses and every condition named in
+// spec.ReadinessGates is present in conditions with a status of True. podPhase is accepted for parity with the
+// kubelet's signature but is not otherwise consulted here; terminal-phase handling (e.g. treating a
+// PodSucceeded pod as trivially ready) is intentionally out of scope and left to the caller.
+func GeneratePodReadyCondition(spec *corev1.PodSpec, conditions []corev1.PodCondition, containerStatuses []corev1.ContainerStatus, podPhase corev1.PodPhase) corev1.PodCondition {
+	if notReady := notReadyContainerNames(spec, containerStatuses); len(notReady) > 0 {
+		sort.Strings(notReady)
+		return corev1.PodCondition{
+			Type:    corev1.PodReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  ContainersNotReady,
+			Message: fmt.Sprintf("containers with unready status: %s", notReady),
+		}
+	}
+
+	var unreadyGates []string
+	for _, rg := range spec.ReadinessGates {
+		_, c := GetPodConditionFromList(conditions, rg.ConditionType)
+		if c == nil || c.Status != corev1.ConditionTrue {
+			unreadyGates = append(unreadyGates, string(rg.ConditionType))
+		}
+	}
+	if len(unreadyGates) > 0 {
+		sort.Strings(unreadyGates)
+		return corev1.PodCondition{
+			Type:    corev1.PodReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  ReadinessGatesNotReady,
+			Message: fmt.Sprintf("corresponding condition of pod readiness gate(s) %s does not have status true", strings.Join(unreadyGates, ", ")),
+		}
+	}
+
+	return corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionTrue}
+}
+
+// notReadyContainerNames returns the names of the containers declared in spec that are either missing from
+// containerStatuses or present but not reporting ready.
+func notReadyContainerNames(spec *corev1.PodSpec, containerStatuses []corev1.ContainerStatus) []string {
+	statusByName := make(map[string]corev1.ContainerStatus, len(containerStatuses))
+	for _, cs := range containerStatuses {
+		statusByName[cs.Name] = cs
+	}
+
+	var names []string
+	for _, c := range spec.Containers {
+		if cs, ok := statusByName[c.Name]; !ok || !cs.Ready {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// podSourceAnnotationKey is the annotation key the kubelet sets to record where a pod's spec came from.
+const podSourceAnnotationKey = "kubernetes.io/config.source"
+
+// Pod source values recorded in podSourceAnnotationKey.
+const (
+	// ApiserverSource identifies pods created via the apiserver.
+	ApiserverSource = "api"
+	// FileSource identifies pods created from a static manifest file on the node.
+	FileSource = "file"
+	// HTTPSource identifies pods created from a static manifest fetched over HTTP.
+	HTTPSource = "http"
+)
+
+// GetPodSource returns the source of the given pod, i.e. the value of its podSourceAnnotationKey annotation.
+// Returns an error if the pod has no such annotation.
+func GetPodSource(pod *corev1.Pod) (string, error) {
+	if pod.Annotations != nil {
+		if source, ok := pod.Annotations[podSourceAnnotationKey]; ok {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("cannot get source of pod %q", pod.Name)
+}
+
+// IsStaticPod returns true if the given pod was created from a static manifest rather than the apiserver,
+// i.e. it's a static pod or its mirror pod. Such pods can't be evicted or scaled by controllers.
+func IsStaticPod(pod *corev1.Pod) bool {
+	source, err := GetPodSource(pod)
+	return err == nil && source != ApiserverSource
+}
@@ -0,0 +1,325 @@
+/*
+ Copyright 2023 The Kapacity Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func readyPod(lastTransitionTime metav1.Time) *corev1.Pod {
+	return &corev1.Pod{
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{
+					Type:               corev1.PodReady,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: lastTransitionTime,
+				},
+			},
+		},
+	}
+}
+
+func TestIsPodAvailable(t *testing.T) {
+	now := metav1.Now()
+
+	tests := map[string]struct {
+		pod             *corev1.Pod
+		minReadySeconds int32
+		now             metav1.Time
+		want            bool
+	}{
+		"not ready": {
+			pod:             &corev1.Pod{},
+			minReadySeconds: 10,
+			now:             now,
+			want:            false,
+		},
+		"ready, zero minReadySeconds, now equals LastTransitionTime": {
+			pod:             readyPod(now),
+			minReadySeconds: 0,
+			now:             now,
+			want:            true,
+		},
+		"ready, minReadySeconds not yet elapsed": {
+			pod:             readyPod(now),
+			minReadySeconds: 10,
+			now:             metav1.NewTime(now.Add(5 * time.Second)),
+			want:            false,
+		},
+		"ready, minReadySeconds elapsed": {
+			pod:             readyPod(now),
+			minReadySeconds: 10,
+			now:             metav1.NewTime(now.Add(11 * time.Second)),
+			want:            true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsPodAvailable(tt.pod, tt.minReadySeconds, tt.now); got != tt.want {
+				t.Errorf("IsPodAvailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratePodReadyCondition(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "app"}},
+	}
+	specWithGate := &corev1.PodSpec{
+		Containers:     []corev1.Container{{Name: "app"}},
+		ReadinessGates: []corev1.PodReadinessGate{{ConditionType: "my-gate"}},
+	}
+
+	tests := map[string]struct {
+		spec              *corev1.PodSpec
+		conditions        []corev1.PodCondition
+		containerStatuses []corev1.ContainerStatus
+		podPhase          corev1.PodPhase
+		wantStatus        corev1.ConditionStatus
+		wantReason        string
+	}{
+		"container not ready": {
+			spec:              spec,
+			containerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: false}},
+			podPhase:          corev1.PodRunning,
+			wantStatus:        corev1.ConditionFalse,
+			wantReason:        ContainersNotReady,
+		},
+		"container ready but pod phase not yet Running does not falsely flag it": {
+			spec:              spec,
+			containerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+			podPhase:          corev1.PodPending,
+			wantStatus:        corev1.ConditionTrue,
+		},
+		"containers ready, no readiness gates": {
+			spec:              spec,
+			containerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+			podPhase:          corev1.PodRunning,
+			wantStatus:        corev1.ConditionTrue,
+		},
+		"containers ready, readiness gate missing": {
+			spec:              specWithGate,
+			containerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+			podPhase:          corev1.PodRunning,
+			wantStatus:        corev1.ConditionFalse,
+			wantReason:        ReadinessGatesNotReady,
+		},
+		"containers ready, readiness gate true": {
+			spec:              specWithGate,
+			conditions:        []corev1.PodCondition{{Type: "my-gate", Status: corev1.ConditionTrue}},
+			containerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+			podPhase:          corev1.PodRunning,
+			wantStatus:        corev1.ConditionTrue,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := GeneratePodReadyCondition(tt.spec, tt.conditions, tt.containerStatuses, tt.podPhase)
+			if got.Type != corev1.PodReady {
+				t.Errorf("Type = %v, want %v", got.Type, corev1.PodReady)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", got.Status, tt.wantStatus)
+			}
+			if tt.wantReason != "" && got.Reason != tt.wantReason {
+				t.Errorf("Reason = %v, want %v", got.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func podWithPhase(phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{Status: corev1.PodStatus{Phase: phase}}
+}
+
+func TestFilterActivePods(t *testing.T) {
+	pods := []*corev1.Pod{
+		podWithPhase(corev1.PodRunning),
+		podWithPhase(corev1.PodSucceeded),
+		podWithPhase(corev1.PodFailed),
+		podWithPhase(corev1.PodPending),
+	}
+
+	got := FilterActivePods(pods)
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+	if got[0] != pods[0] || got[1] != pods[3] {
+		t.Errorf("FilterActivePods() = %v, want [pods[0], pods[3]]", got)
+	}
+}
+
+func TestFilterReadyPods(t *testing.T) {
+	tests := map[string]struct {
+		pods []*corev1.Pod
+		want int
+	}{
+		"empty": {
+			pods: nil,
+			want: 0,
+		},
+		"none ready": {
+			pods: []*corev1.Pod{{}, {}},
+			want: 0,
+		},
+		"some ready": {
+			pods: []*corev1.Pod{readyPod(metav1.Now()), {}},
+			want: 1,
+		},
+		"all ready": {
+			pods: []*corev1.Pod{readyPod(metav1.Now()), readyPod(metav1.Now())},
+			want: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := FilterReadyPods(tt.pods); len(got) != tt.want {
+				t.Errorf("len(FilterReadyPods()) = %d, want %d", len(got), tt.want)
+			}
+			if got := CountReadyPods(tt.pods); got != tt.want {
+				t.Errorf("CountReadyPods() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterAvailablePods(t *testing.T) {
+	now := metav1.Now()
+	longReady := readyPod(metav1.NewTime(now.Add(-time.Minute)))
+	justReady := readyPod(now)
+
+	tests := map[string]struct {
+		pods            []*corev1.Pod
+		minReadySeconds int32
+		want            int
+	}{
+		"empty": {
+			pods: nil,
+			want: 0,
+		},
+		"none available, minReadySeconds not elapsed": {
+			pods:            []*corev1.Pod{justReady},
+			minReadySeconds: 10,
+			want:            0,
+		},
+		"some available": {
+			pods:            []*corev1.Pod{longReady, justReady},
+			minReadySeconds: 10,
+			want:            1,
+		},
+		"all available, zero minReadySeconds": {
+			pods:            []*corev1.Pod{longReady, justReady},
+			minReadySeconds: 0,
+			want:            2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := FilterAvailablePods(tt.pods, tt.minReadySeconds, now); len(got) != tt.want {
+				t.Errorf("len(FilterAvailablePods()) = %d, want %d", len(got), tt.want)
+			}
+			if got := CountAvailablePods(tt.pods, tt.minReadySeconds, now); got != tt.want {
+				t.Errorf("CountAvailablePods() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPodSource(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		want        string
+		wantErr     bool
+	}{
+		"nil annotations": {
+			annotations: nil,
+			wantErr:     true,
+		},
+		"annotation absent": {
+			annotations: map[string]string{"other": "value"},
+			wantErr:     true,
+		},
+		"apiserver source": {
+			annotations: map[string]string{podSourceAnnotationKey: ApiserverSource},
+			want:        ApiserverSource,
+		},
+		"file source": {
+			annotations: map[string]string{podSourceAnnotationKey: FileSource},
+			want:        FileSource,
+		},
+		"http source": {
+			annotations: map[string]string{podSourceAnnotationKey: HTTPSource},
+			want:        HTTPSource,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			got, err := GetPodSource(pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetPodSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsStaticPod(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		want        bool
+	}{
+		"nil annotations": {
+			annotations: nil,
+			want:        false,
+		},
+		"apiserver source": {
+			annotations: map[string]string{podSourceAnnotationKey: ApiserverSource},
+			want:        false,
+		},
+		"file source": {
+			annotations: map[string]string{podSourceAnnotationKey: FileSource},
+			want:        true,
+		},
+		"http source": {
+			annotations: map[string]string{podSourceAnnotationKey: HTTPSource},
+			want:        true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if got := IsStaticPod(pod); got != tt.want {
+				t.Errorf("IsStaticPod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}